@@ -0,0 +1,217 @@
+package urlshort
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"go.mongodb.org/mongo-driver/mongo"
+	"gopkg.in/mgo.v2/bson"
+)
+
+// adminStore is the subset of *RedirectoryDatabase that AdminHandler
+// needs. Accepting this interface rather than the concrete type lets
+// AdminHandler's routing and error-translation logic be tested without
+// a Mongo connection.
+type adminStore interface {
+	InsertOne(ctx context.Context, v interface{}) error
+	FindByPath(ctx context.Context, path string, v interface{}) error
+	UpdateByPath(ctx context.Context, path string, update bson.M) error
+	DeleteByPath(ctx context.Context, path string) error
+	ListPage(ctx context.Context, v interface{}, limit, offset int64) error
+}
+
+// ErrUnauthorized is returned by an Authenticator when a request
+// fails authentication.
+var ErrUnauthorized = errors.New("urlshort: unauthorized")
+
+// Authenticator validates incoming admin requests, returning
+// ErrUnauthorized (or a wrapping error) when authentication fails.
+type Authenticator interface {
+	Authenticate(r *http.Request) error
+}
+
+// BearerTokenAuthenticator is an Authenticator that requires an
+// `Authorization: Bearer <Token>` header matching Token, e.g. a
+// secret loaded via godotenv as in Task4.
+type BearerTokenAuthenticator struct {
+	Token string
+}
+
+func (a BearerTokenAuthenticator) Authenticate(r *http.Request) error {
+	const prefix = "Bearer "
+
+	header := r.Header.Get("Authorization")
+	if !strings.HasPrefix(header, prefix) || strings.TrimPrefix(header, prefix) != a.Token {
+		return ErrUnauthorized
+	}
+
+	return nil
+}
+
+// Problem is an RFC 7807 problem+json error body.
+type Problem struct {
+	Type   string `json:"type,omitempty"`
+	Title  string `json:"title"`
+	Status int    `json:"status"`
+	Detail string `json:"detail,omitempty"`
+}
+
+func writeProblem(w http.ResponseWriter, status int, title, detail string) {
+	w.Header().Set("Content-Type", "application/problem+json")
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(Problem{Title: title, Status: status, Detail: detail})
+}
+
+// AdminHandler mounts a CRUD REST API over r:
+//
+//	POST   /admin/redirects
+//	GET    /admin/redirects
+//	GET    /admin/redirects/{path}
+//	PUT    /admin/redirects/{path}
+//	DELETE /admin/redirects/{path}
+//
+// Request and response bodies are JSON-encoded Redirects. Every
+// request is checked against auth before being served; pass a nil
+// auth to leave the admin surface open (not recommended outside of
+// tests).
+func AdminHandler(r adminStore, auth Authenticator) http.Handler {
+	mux := http.NewServeMux()
+
+	mux.HandleFunc("/admin/redirects", func(w http.ResponseWriter, req *http.Request) {
+		switch req.Method {
+		case http.MethodPost:
+			adminCreateRedirect(w, req, r)
+		case http.MethodGet:
+			adminListRedirects(w, req, r)
+		default:
+			writeProblem(w, http.StatusMethodNotAllowed, "Method Not Allowed", "")
+		}
+	})
+
+	mux.HandleFunc("/admin/redirects/", func(w http.ResponseWriter, req *http.Request) {
+		path := strings.TrimPrefix(req.URL.Path, "/admin/redirects/")
+		if path == "" {
+			writeProblem(w, http.StatusBadRequest, "Bad Request", "missing redirect path")
+			return
+		}
+		path = "/" + path
+
+		switch req.Method {
+		case http.MethodGet:
+			adminGetRedirect(w, req, r, path)
+		case http.MethodPut:
+			adminUpdateRedirect(w, req, r, path)
+		case http.MethodDelete:
+			adminDeleteRedirect(w, req, r, path)
+		default:
+			writeProblem(w, http.StatusMethodNotAllowed, "Method Not Allowed", "")
+		}
+	})
+
+	if auth == nil {
+		return mux
+	}
+
+	return authenticate(auth, mux)
+}
+
+func authenticate(auth Authenticator, next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if err := auth.Authenticate(r); err != nil {
+			writeProblem(w, http.StatusUnauthorized, "Unauthorized", err.Error())
+			return
+		}
+
+		next.ServeHTTP(w, r)
+	})
+}
+
+func adminCreateRedirect(w http.ResponseWriter, req *http.Request, r adminStore) {
+	var redirect Redirect
+	if err := json.NewDecoder(req.Body).Decode(&redirect); err != nil {
+		writeProblem(w, http.StatusBadRequest, "Bad Request", err.Error())
+		return
+	}
+
+	if err := r.InsertOne(req.Context(), redirect); err != nil {
+		writeProblem(w, http.StatusConflict, "Conflict", err.Error())
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusCreated)
+	json.NewEncoder(w).Encode(redirect)
+}
+
+func adminListRedirects(w http.ResponseWriter, req *http.Request, r adminStore) {
+	limit, _ := strconv.ParseInt(req.URL.Query().Get("limit"), 10, 64)
+	offset, _ := strconv.ParseInt(req.URL.Query().Get("offset"), 10, 64)
+
+	var redirects []Redirect
+	if err := r.ListPage(req.Context(), &redirects, limit, offset); err != nil {
+		writeProblem(w, http.StatusInternalServerError, "Internal Server Error", err.Error())
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(redirects)
+}
+
+func adminGetRedirect(w http.ResponseWriter, req *http.Request, r adminStore, path string) {
+	var redirect Redirect
+	if err := r.FindByPath(req.Context(), path, &redirect); err != nil {
+		writeNotFoundOrError(w, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(redirect)
+}
+
+func adminUpdateRedirect(w http.ResponseWriter, req *http.Request, r adminStore, path string) {
+	var redirect Redirect
+	if err := json.NewDecoder(req.Body).Decode(&redirect); err != nil {
+		writeProblem(w, http.StatusBadRequest, "Bad Request", err.Error())
+		return
+	}
+
+	redirect.Path = path
+
+	update := bson.M{
+		"url":     redirect.URL,
+		"pattern": redirect.Pattern,
+		"host":    redirect.Host,
+		"mode":    redirect.Mode,
+		"status":  redirect.Status,
+	}
+
+	if err := r.UpdateByPath(req.Context(), path, update); err != nil {
+		writeNotFoundOrError(w, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(redirect)
+}
+
+func adminDeleteRedirect(w http.ResponseWriter, req *http.Request, r adminStore, path string) {
+	if err := r.DeleteByPath(req.Context(), path); err != nil {
+		writeNotFoundOrError(w, err)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func writeNotFoundOrError(w http.ResponseWriter, err error) {
+	if err == mongo.ErrNoDocuments {
+		writeProblem(w, http.StatusNotFound, "Not Found", "no redirect with that path")
+		return
+	}
+
+	writeProblem(w, http.StatusInternalServerError, "Internal Server Error", err.Error())
+}
@@ -0,0 +1,146 @@
+package urlshort
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"go.mongodb.org/mongo-driver/mongo"
+	"gopkg.in/mgo.v2/bson"
+)
+
+// stubAdminStore is a DB-free adminStore for exercising AdminHandler's
+// routing and error-translation logic.
+type stubAdminStore struct {
+	insertOneErr    error
+	findByPathErr   error
+	findByPathRes   Redirect
+	updateByPathErr error
+	deleteByPathErr error
+	listPageRes     []Redirect
+	listPageErr     error
+}
+
+func (s *stubAdminStore) InsertOne(ctx context.Context, v interface{}) error {
+	return s.insertOneErr
+}
+
+func (s *stubAdminStore) FindByPath(ctx context.Context, path string, v interface{}) error {
+	if s.findByPathErr != nil {
+		return s.findByPathErr
+	}
+	*v.(*Redirect) = s.findByPathRes
+	return nil
+}
+
+func (s *stubAdminStore) UpdateByPath(ctx context.Context, path string, update bson.M) error {
+	return s.updateByPathErr
+}
+
+func (s *stubAdminStore) DeleteByPath(ctx context.Context, path string) error {
+	return s.deleteByPathErr
+}
+
+func (s *stubAdminStore) ListPage(ctx context.Context, v interface{}, limit, offset int64) error {
+	if s.listPageErr != nil {
+		return s.listPageErr
+	}
+	*v.(*[]Redirect) = s.listPageRes
+	return nil
+}
+
+func TestAdminHandlerUnauthorized(t *testing.T) {
+	handler := AdminHandler(&stubAdminStore{}, BearerTokenAuthenticator{Token: "secret"})
+
+	req, err := http.NewRequest("GET", "/admin/redirects", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	rr := httptest.NewRecorder()
+	handler.ServeHTTP(rr, req)
+
+	if status := rr.Code; status != http.StatusUnauthorized {
+		t.Errorf("Expected status code %v, got %v", http.StatusUnauthorized, status)
+	}
+
+	if ct := rr.Header().Get("Content-Type"); ct != "application/problem+json" {
+		t.Errorf("Expected Content-Type application/problem+json, got %s", ct)
+	}
+}
+
+func TestAdminHandlerNilAuthAllowsRequest(t *testing.T) {
+	handler := AdminHandler(&stubAdminStore{listPageRes: []Redirect{}}, nil)
+
+	req, err := http.NewRequest("GET", "/admin/redirects", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	rr := httptest.NewRecorder()
+	handler.ServeHTTP(rr, req)
+
+	if status := rr.Code; status != http.StatusOK {
+		t.Errorf("Expected status code %v, got %v", http.StatusOK, status)
+	}
+}
+
+func TestAdminHandlerMethodNotAllowed(t *testing.T) {
+	handler := AdminHandler(&stubAdminStore{}, nil)
+
+	for _, path := range []string{"/admin/redirects", "/admin/redirects/foo"} {
+		req, err := http.NewRequest("PATCH", path, nil)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		rr := httptest.NewRecorder()
+		handler.ServeHTTP(rr, req)
+
+		if status := rr.Code; status != http.StatusMethodNotAllowed {
+			t.Errorf("%s: expected status code %v, got %v", path, http.StatusMethodNotAllowed, status)
+		}
+	}
+}
+
+func TestAdminHandlerNotFound(t *testing.T) {
+	handler := AdminHandler(&stubAdminStore{findByPathErr: mongo.ErrNoDocuments}, nil)
+
+	req, err := http.NewRequest("GET", "/admin/redirects/missing", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	rr := httptest.NewRecorder()
+	handler.ServeHTTP(rr, req)
+
+	if status := rr.Code; status != http.StatusNotFound {
+		t.Errorf("Expected status code %v, got %v", http.StatusNotFound, status)
+	}
+
+	if ct := rr.Header().Get("Content-Type"); ct != "application/problem+json" {
+		t.Errorf("Expected Content-Type application/problem+json, got %s", ct)
+	}
+}
+
+func TestAdminHandlerGetSuccess(t *testing.T) {
+	want := Redirect{Path: "/found", URL: "https://example.com"}
+	handler := AdminHandler(&stubAdminStore{findByPathRes: want}, nil)
+
+	req, err := http.NewRequest("GET", "/admin/redirects/found", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	rr := httptest.NewRecorder()
+	handler.ServeHTTP(rr, req)
+
+	if status := rr.Code; status != http.StatusOK {
+		t.Errorf("Expected status code %v, got %v", http.StatusOK, status)
+	}
+
+	if ct := rr.Header().Get("Content-Type"); ct != "application/json" {
+		t.Errorf("Expected Content-Type application/json, got %s", ct)
+	}
+}
@@ -4,6 +4,7 @@ import (
 	"context"
 
 	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
 	"gopkg.in/mgo.v2/bson"
 )
 
@@ -13,10 +14,21 @@ type RedirectoryDatabase struct {
 
 var redirectoryCollectionName = "redirect"
 
-func NewRedirectoryDatabase(db *mongo.Database) *RedirectoryDatabase {
+// NewRedirectoryDatabase builds a RedirectoryDatabase backed by db,
+// ensuring a unique index on path exists so InsertOne/InsertMany
+// reject duplicate redirects.
+func NewRedirectoryDatabase(ctx context.Context, db *mongo.Database) (*RedirectoryDatabase, error) {
 	svc := RedirectoryDatabase{db}
 
-	return &svc
+	_, err := db.Collection(redirectoryCollectionName).Indexes().CreateOne(ctx, mongo.IndexModel{
+		Keys:    bson.M{"path": 1},
+		Options: options.Index().SetUnique(true),
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return &svc, nil
 }
 
 func (r *RedirectoryDatabase) InsertMany(ctx context.Context, v []interface{}) error {
@@ -42,3 +54,178 @@ func (r *RedirectoryDatabase) Find(ctx context.Context, v interface{}, filter bs
 
 	return err
 }
+
+// InsertOne inserts a single redirect document.
+func (r *RedirectoryDatabase) InsertOne(ctx context.Context, v interface{}) error {
+	_, err := r.db.Collection(redirectoryCollectionName).InsertOne(ctx, v)
+
+	return err
+}
+
+// FindByPath finds the redirect document with the given path and
+// decodes it into v. Returns mongo.ErrNoDocuments if no such redirect
+// exists.
+func (r *RedirectoryDatabase) FindByPath(ctx context.Context, path string, v interface{}) error {
+	return r.db.Collection(redirectoryCollectionName).FindOne(ctx, bson.M{"path": path}).Decode(v)
+}
+
+// UpdateByPath applies update as a $set against the redirect document
+// with the given path. Returns mongo.ErrNoDocuments if no such
+// redirect exists.
+func (r *RedirectoryDatabase) UpdateByPath(ctx context.Context, path string, update bson.M) error {
+	res, err := r.db.Collection(redirectoryCollectionName).UpdateOne(ctx, bson.M{"path": path}, bson.M{"$set": update})
+	if err != nil {
+		return err
+	}
+
+	if res.MatchedCount == 0 {
+		return mongo.ErrNoDocuments
+	}
+
+	return nil
+}
+
+// DeleteByPath deletes the redirect document with the given path.
+// Returns mongo.ErrNoDocuments if no such redirect exists.
+func (r *RedirectoryDatabase) DeleteByPath(ctx context.Context, path string) error {
+	res, err := r.db.Collection(redirectoryCollectionName).DeleteOne(ctx, bson.M{"path": path})
+	if err != nil {
+		return err
+	}
+
+	if res.DeletedCount == 0 {
+		return mongo.ErrNoDocuments
+	}
+
+	return nil
+}
+
+// ListPage finds up to limit redirect documents, skipping offset of
+// them, and decodes the results into v. A non-positive limit fetches
+// all remaining documents.
+func (r *RedirectoryDatabase) ListPage(ctx context.Context, v interface{}, limit, offset int64) error {
+	findOpts := options.Find().SetSkip(offset)
+	if limit > 0 {
+		findOpts.SetLimit(limit)
+	}
+
+	cursor, err := r.db.Collection(redirectoryCollectionName).Find(ctx, bson.M{}, findOpts)
+	if err != nil {
+		return err
+	}
+
+	return cursor.All(ctx, v)
+}
+
+// Lookup implements Store, satisfying a single-path redirect lookup
+// against the Mongo collection.
+func (r *RedirectoryDatabase) Lookup(ctx context.Context, path string) (string, bool, error) {
+	var redirect Redirect
+
+	err := r.FindByPath(ctx, path, &redirect)
+	if err == mongo.ErrNoDocuments {
+		return "", false, nil
+	}
+	if err != nil {
+		return "", false, err
+	}
+
+	return redirect.URL, true, nil
+}
+
+// List implements Store, returning every redirect in the collection.
+func (r *RedirectoryDatabase) List(ctx context.Context) ([]Redirect, error) {
+	var redirects []Redirect
+
+	err := r.Find(ctx, &redirects, bson.M{})
+
+	return redirects, err
+}
+
+// Upsert implements Store, creating or replacing the document at
+// redirect.Path.
+func (r *RedirectoryDatabase) Upsert(ctx context.Context, redirect Redirect) error {
+	_, err := r.db.Collection(redirectoryCollectionName).ReplaceOne(
+		ctx,
+		bson.M{"path": redirect.Path},
+		redirect,
+		options.Replace().SetUpsert(true),
+	)
+
+	return err
+}
+
+// Delete implements Store on top of DeleteByPath, treating a missing
+// document as success rather than mongo.ErrNoDocuments.
+func (r *RedirectoryDatabase) Delete(ctx context.Context, path string) error {
+	err := r.DeleteByPath(ctx, path)
+	if err == mongo.ErrNoDocuments {
+		return nil
+	}
+
+	return err
+}
+
+// changeEvent is the subset of a Mongo change stream event Watch
+// cares about.
+type changeEvent struct {
+	OperationType string   `bson:"operationType"`
+	FullDocument  Redirect `bson:"fullDocument"`
+	DocumentKey   struct {
+		Path string `bson:"path"`
+	} `bson:"documentKey"`
+}
+
+// Watch implements Store using a Mongo change stream over the
+// redirect collection. The target deployment must be a replica set or
+// sharded cluster, as change streams require one.
+func (r *RedirectoryDatabase) Watch(ctx context.Context) (<-chan StoreEvent, error) {
+	streamOpts := options.ChangeStream().SetFullDocument(options.UpdateLookup)
+
+	stream, err := r.db.Collection(redirectoryCollectionName).Watch(ctx, mongo.Pipeline{}, streamOpts)
+	if err != nil {
+		return nil, err
+	}
+
+	events := make(chan StoreEvent)
+
+	go func() {
+		defer close(events)
+		defer stream.Close(ctx)
+
+		for stream.Next(ctx) {
+			var change changeEvent
+			if err := stream.Decode(&change); err != nil {
+				continue
+			}
+
+			switch change.OperationType {
+			case "insert", "update", "replace":
+				// SetFullDocument(UpdateLookup) above makes Mongo
+				// populate FullDocument for "update" events too, but
+				// fall back to an explicit re-fetch in case the
+				// looked-up document was already deleted.
+				if change.FullDocument.Path == "" {
+					var redirect Redirect
+					if err := r.FindByPath(ctx, change.DocumentKey.Path, &redirect); err != nil {
+						continue
+					}
+					change.FullDocument = redirect
+				}
+				select {
+				case events <- StoreEvent{Type: StoreEventUpsert, Redirect: change.FullDocument}:
+				case <-ctx.Done():
+					return
+				}
+			case "delete":
+				select {
+				case events <- StoreEvent{Type: StoreEventDelete, Path: change.DocumentKey.Path}:
+				case <-ctx.Done():
+					return
+				}
+			}
+		}
+	}()
+
+	return events, nil
+}
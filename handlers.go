@@ -2,13 +2,11 @@ package urlshort
 
 import (
 	"bufio"
-	"context"
 	"encoding/json"
 	"log"
 	"net/http"
 	"os"
 
-	"gopkg.in/mgo.v2/bson"
 	"gopkg.in/yaml.v2"
 )
 
@@ -23,18 +21,51 @@ func MapHandler(pathsToUrls map[string]string, fallback http.Handler) http.Handl
 		path := r.URL.Path
 
 		if url, ok := pathsToUrls[path]; ok {
+			if rec, ok := matchRecorderFromContext(r.Context()); ok {
+				rec.record(&Redirect{Path: path, URL: url}, url, "hit")
+			}
+
 			http.Redirect(w, r, url, http.StatusFound)
-		} else {
-			fallback.ServeHTTP(w, r)
+			return
+		}
+
+		if rec, ok := matchRecorderFromContext(r.Context()); ok {
+			rec.record(nil, "", "miss")
 		}
+
+		fallback.ServeHTTP(w, r)
 	}
 }
 
 type Redirect struct {
-	Path string `yaml:"path" json:"path"`
+	Path string `yaml:"path,omitempty" json:"path,omitempty"`
 	URL  string `yaml:"url" json:"url"`
+
+	// Pattern, when set, makes this an entry for PatternHandler
+	// instead of an exact-match entry: Path is ignored and the
+	// request path is matched against Pattern instead.
+	Pattern string `yaml:"pattern,omitempty" json:"pattern,omitempty"`
+
+	// Host, when set, restricts a Pattern entry to requests for
+	// that Host. Ignored for exact-match entries.
+	Host string `yaml:"host,omitempty" json:"host,omitempty"`
+
+	// Mode selects how a match is served: ModeRedirect (the
+	// default) issues an HTTP redirect to URL, while ModeProxy
+	// transparently forwards the request to URL. See ProxyHandler.
+	Mode string `yaml:"mode,omitempty" json:"mode,omitempty"`
+
+	// Status is the HTTP status code used for a ModeRedirect match.
+	// Defaults to http.StatusFound (302) when zero.
+	Status int `yaml:"status,omitempty" json:"status,omitempty"`
 }
 
+// Redirect.Mode values.
+const (
+	ModeRedirect = "redirect"
+	ModeProxy    = "proxy"
+)
+
 // YAMLHandler will parse the provided YAML and then return
 // an http.HandlerFunc (which also implements http.Handler)
 // that will attempt to map any paths to their corresponding
@@ -93,29 +124,6 @@ func JsonHandler(json []byte, fallback http.Handler) (http.HandlerFunc, error) {
 	return MapHandler(pathsToUrls, fallback), nil
 }
 
-// DbHandler retrieves path -> url mappings from a provided
-// database and then returns an http.HandlerFunc that will
-// attempt to map any paths to their corresponding URL. If
-// the path is not provided in the JSON, then thefallback
-// http.Handler will be called instead.
-//
-// Expects a database result of []Redirect
-// Errors returned are related to the query of the database
-// or the unmarshalling of the result into our []Redirect
-func DbHandler(ctx context.Context, r *RedirectoryDatabase, fallback http.Handler) (http.HandlerFunc, error) {
-	var redirects []Redirect
-
-	// Find all redirect collection items using bson.M{}
-	err := r.Find(ctx, &redirects, bson.M{})
-	if err != nil {
-		return nil, err
-	}
-
-	pathsToUrls := RedirectsToMap(redirects)
-
-	return MapHandler(pathsToUrls, fallback), nil
-}
-
 // ParseYaml takes raw yaml bytes array and parses
 // it into a given interface
 func ParseYaml(yml []byte, v interface{}) error {
@@ -31,6 +31,8 @@ func main() {
 		Task3(*pathPtr)
 	case 4:
 		Task4()
+	case 6:
+		Task6()
 	default:
 		Task1()
 	}
@@ -119,9 +121,9 @@ func Task3(path string) {
 	http.ListenAndServe(":8080", jsonHandler)
 }
 
-// Task 4 sets up and uses a mongoDB database to read in the paths to 
-// urls map. The DbHandler reads from the database each time the handler 
-// is called.
+// Task 4 sets up and uses a mongoDB database to read in the paths to
+// urls map. The StoreHandler reads from the database once at startup;
+// see Task6 for a handler that stays in sync with the database.
 func Task4() {
 	// Load in .env variables for secret variable safety
 	err := godotenv.Load(".env")
@@ -164,7 +166,10 @@ func Task4() {
 	db.Collection("redirect").Drop(ctx)
 
 	// Create our db struct
-	r := urlshort.NewRedirectoryDatabase(db)
+	r, err := urlshort.NewRedirectoryDatabase(ctx, db)
+	if err != nil {
+		log.Fatal(err)
+	}
 
 	redirects := []urlshort.Redirect{
 		urlshort.Redirect{
@@ -189,13 +194,13 @@ func Task4() {
 	mux := defaultMux()
 
 	// Create handler which looks up our redirections in the collection
-	dbHandler, err := urlshort.DbHandler(ctx, r, mux)
+	storeHandler, err := urlshort.StoreHandler(ctx, r, mux)
 	if err != nil {
 		log.Fatal(err)
 	}
 
 	fmt.Println("Starting the server on :8080")
-	http.ListenAndServe(":8080", dbHandler)
+	http.ListenAndServe(":8080", storeHandler)
 }
 
 // Task 5 is the same as task 2 but safely reads in a file
@@ -221,3 +226,72 @@ func Task5(path string) {
 	http.ListenAndServe(":8080", mapHandler)
 }
 
+// Task 6 mounts the REST admin API from AdminHandler under /admin,
+// guarded by a bearer token loaded from .env, alongside a
+// StoreHandler serving redirects under / that stays in sync with the
+// database via change streams rather than a timed reload.
+func Task6() {
+	// Load in .env variables for secret variable safety
+	err := godotenv.Load(".env")
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	dbHost := os.Getenv("DB_HOST")
+	dbPort := os.Getenv("DB_PORT")
+	dbName := os.Getenv("DB_NAME")
+	adminToken := os.Getenv("ADMIN_TOKEN")
+
+	// Create mongoDB client
+	mongoURI := fmt.Sprintf("mongodb://%s:%s", dbHost, dbPort)
+	client, err := mongo.NewClient(options.Client().ApplyURI(mongoURI))
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	// Create timeout context, bounding only the startup steps below;
+	// WatchStore and the server itself outlive this and use
+	// context.Background() instead.
+	setupCtx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
+
+	// Connect to mongoDB client
+	err = client.Connect(setupCtx)
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	// Defer disconnect until function ends
+	defer client.Disconnect(context.Background())
+
+	db := client.Database(dbName)
+
+	// Create our db struct, which also ensures the unique index on path
+	r, err := urlshort.NewRedirectoryDatabase(setupCtx, db)
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	mux := defaultMux()
+
+	// Create handler which looks up our redirections in the collection
+	storeHandler, err := urlshort.StoreHandler(setupCtx, r, mux)
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	// Keep the compiled map in sync with the database incrementally
+	// instead of polling a full reload.
+	go storeHandler.WatchStore(context.Background(), r)
+
+	auth := urlshort.BearerTokenAuthenticator{Token: adminToken}
+	adminHandler := urlshort.AdminHandler(r, auth)
+
+	top := http.NewServeMux()
+	top.Handle("/admin/", adminHandler)
+	top.Handle("/", storeHandler)
+
+	fmt.Println("Starting the server on :8080")
+	http.ListenAndServe(":8080", top)
+}
+
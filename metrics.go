@@ -0,0 +1,58 @@
+package urlshort
+
+import (
+	"net/http"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+)
+
+var (
+	requestsTotal = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Name: "urlshort_requests_total",
+		Help: "Total requests served, labeled by result.",
+	}, []string{"result"})
+
+	requestDuration = prometheus.NewHistogram(prometheus.HistogramOpts{
+		Name:    "urlshort_request_duration_seconds",
+		Help:    "Request duration in seconds.",
+		Buckets: prometheus.DefBuckets,
+	})
+)
+
+func init() {
+	prometheus.MustRegister(requestsTotal, requestDuration)
+}
+
+// Metrics wraps handler, recording urlshort_requests_total (labeled
+// "hit", "miss", or "proxy_error" using MapHandler's context
+// recorder and the response status) and urlshort_request_duration_seconds
+// for every request, and serves them at /metrics.
+func Metrics(handler http.Handler) http.Handler {
+	metricsHandler := promhttp.Handler()
+
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/metrics" {
+			metricsHandler.ServeHTTP(w, r)
+			return
+		}
+
+		start := time.Now()
+
+		ctx, rec := withMatchRecorder(r.Context())
+		sw := &statusWriter{ResponseWriter: w}
+
+		handler.ServeHTTP(sw, r.WithContext(ctx))
+
+		_, _, result := rec.snapshot()
+		if sw.status >= http.StatusInternalServerError {
+			result = "proxy_error"
+		} else if result == "" {
+			result = "miss"
+		}
+
+		requestsTotal.WithLabelValues(result).Inc()
+		requestDuration.Observe(time.Since(start).Seconds())
+	})
+}
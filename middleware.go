@@ -0,0 +1,175 @@
+package urlshort
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"os"
+	"strings"
+	"sync"
+	"time"
+)
+
+// matchCtxKey is the context key under which a *matchRecorder is
+// stored, letting MapHandler publish the redirect it matched (if any)
+// without middleware having to duplicate the lookup.
+type matchCtxKey struct{}
+
+// matchRecorder captures the outcome of a single request's redirect
+// lookup so that middleware wrapping the handler chain can observe it
+// after the chain returns.
+type matchRecorder struct {
+	mu       sync.Mutex
+	redirect *Redirect
+	target   string
+	result   string // "hit" or "miss"
+}
+
+// withMatchRecorder returns a context carrying a *matchRecorder,
+// reusing one already installed by an outer middleware instead of
+// shadowing it — MapHandler only ever writes to the innermost
+// recorder in ctx, so composed middleware (e.g. Metrics(Logging(h)))
+// must all observe the same recorder.
+func withMatchRecorder(ctx context.Context) (context.Context, *matchRecorder) {
+	if rec, ok := matchRecorderFromContext(ctx); ok {
+		return ctx, rec
+	}
+
+	rec := &matchRecorder{}
+	return context.WithValue(ctx, matchCtxKey{}, rec), rec
+}
+
+func matchRecorderFromContext(ctx context.Context) (*matchRecorder, bool) {
+	rec, ok := ctx.Value(matchCtxKey{}).(*matchRecorder)
+	return rec, ok
+}
+
+func (m *matchRecorder) record(redirect *Redirect, target, result string) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.redirect = redirect
+	m.target = target
+	m.result = result
+}
+
+func (m *matchRecorder) snapshot() (redirect *Redirect, target, result string) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	return m.redirect, m.target, m.result
+}
+
+// statusWriter wraps an http.ResponseWriter to record the status code
+// and number of bytes written, for access logging and metrics.
+type statusWriter struct {
+	http.ResponseWriter
+	status int
+	bytes  int
+}
+
+func (w *statusWriter) WriteHeader(status int) {
+	w.status = status
+	w.ResponseWriter.WriteHeader(status)
+}
+
+func (w *statusWriter) Write(b []byte) (int, error) {
+	if w.status == 0 {
+		w.status = http.StatusOK
+	}
+
+	n, err := w.ResponseWriter.Write(b)
+	w.bytes += n
+
+	return n, err
+}
+
+// logLevels orders the levels Logging understands from least to most
+// severe, mirroring a typical LOG_LEVEL env var.
+var logLevels = map[string]int{
+	"debug": 0,
+	"info":  1,
+	"warn":  2,
+	"error": 3,
+}
+
+// getLogLevel reads LOG_LEVEL from the environment, defaulting to
+// "info" when unset or unrecognized.
+func getLogLevel() string {
+	level := strings.ToLower(os.Getenv("LOG_LEVEL"))
+	if _, ok := logLevels[level]; !ok {
+		return "info"
+	}
+
+	return level
+}
+
+// LogOpts configures Logging.
+type LogOpts struct {
+	// Writer receives one JSON line per request. Defaults to
+	// os.Stdout when nil.
+	Writer io.Writer
+
+	// Level gates which access log lines are emitted, following the
+	// same debug/info/warn/error ordering as LOG_LEVEL. Access log
+	// lines are emitted at "info", so setting Level to "warn" or
+	// "error" silences them. Defaults to getLogLevel() when empty.
+	Level string
+}
+
+// logEntry is the JSON shape written by Logging for each request.
+type logEntry struct {
+	Method     string `json:"method"`
+	Path       string `json:"path"`
+	Rule       string `json:"rule,omitempty"`
+	Target     string `json:"target,omitempty"`
+	Status     int    `json:"status"`
+	Bytes      int    `json:"bytes"`
+	DurationMs int64  `json:"duration_ms"`
+}
+
+// Logging wraps handler, writing one structured JSON line per request
+// to opts.Writer containing the method, path, the rule (if any)
+// matched via MapHandler's context recorder, the resolved target URL,
+// response status, bytes written, and duration.
+func Logging(handler http.Handler, opts LogOpts) http.Handler {
+	writer := opts.Writer
+	if writer == nil {
+		writer = os.Stdout
+	}
+
+	level := opts.Level
+	if level == "" {
+		level = getLogLevel()
+	}
+
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		start := time.Now()
+
+		ctx, rec := withMatchRecorder(r.Context())
+		sw := &statusWriter{ResponseWriter: w}
+
+		handler.ServeHTTP(sw, r.WithContext(ctx))
+
+		if logLevels[level] > logLevels["info"] {
+			return
+		}
+
+		redirect, target, _ := rec.snapshot()
+
+		entry := logEntry{
+			Method:     r.Method,
+			Path:       r.URL.Path,
+			Target:     target,
+			Status:     sw.status,
+			Bytes:      sw.bytes,
+			DurationMs: time.Since(start).Milliseconds(),
+		}
+		if redirect != nil {
+			entry.Rule = redirect.Path
+		}
+
+		json.NewEncoder(writer).Encode(entry)
+	})
+}
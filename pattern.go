@@ -0,0 +1,177 @@
+package urlshort
+
+import (
+	"net/http"
+	"regexp"
+	"strings"
+)
+
+// Pattern describes a rule that matches request paths (and optionally
+// a specific Host) against a compiled regular expression, substituting
+// any captured groups into URL to build the redirect target.
+//
+// Pattern may either be a full regular expression (anchored with `^`)
+// such as `^/gh/([^/]+)/([^/]+)$`, or a glob-style template using
+// `:name` for a single path segment and `*name` for the remainder of
+// the path, e.g. `/user/:id/repo/*rest`. Both forms populate numbered
+// ($1, $2, ...) and, for the glob form, named ($id, $rest) groups that
+// URL can reference.
+type Pattern struct {
+	Pattern string `yaml:"pattern" json:"pattern"`
+	URL     string `yaml:"url" json:"url"`
+	Host    string `yaml:"host,omitempty" json:"host,omitempty"`
+}
+
+// compiledPattern is a Pattern with its regular expression pre-compiled.
+type compiledPattern struct {
+	re   *regexp.Regexp
+	url  []byte
+	host string
+}
+
+// segmentRe matches the `:name` and `*name` glob tokens within a
+// pattern template.
+var segmentRe = regexp.MustCompile(`:([A-Za-z_][A-Za-z0-9_]*)|\*([A-Za-z_][A-Za-z0-9_]*)`)
+
+// globToRegexp converts a glob-style path template into an anchored
+// regular expression, quoting everything that isn't a `:name` or
+// `*name` token.
+func globToRegexp(glob string) string {
+	var sb strings.Builder
+	sb.WriteString("^")
+
+	last := 0
+	for _, loc := range segmentRe.FindAllStringSubmatchIndex(glob, -1) {
+		sb.WriteString(regexp.QuoteMeta(glob[last:loc[0]]))
+
+		if loc[2] != -1 {
+			name := glob[loc[2]:loc[3]]
+			sb.WriteString("(?P<" + name + ">[^/]+)")
+		} else {
+			name := glob[loc[4]:loc[5]]
+			sb.WriteString("(?P<" + name + ">.*)")
+		}
+
+		last = loc[1]
+	}
+	sb.WriteString(regexp.QuoteMeta(glob[last:]))
+	sb.WriteString("$")
+
+	return sb.String()
+}
+
+// compilePattern compiles a Pattern's template into a regular
+// expression, treating any template already anchored with `^` as a
+// raw regular expression and everything else as a glob-style template.
+func compilePattern(p Pattern) (*compiledPattern, error) {
+	expr := p.Pattern
+	if !strings.HasPrefix(expr, "^") {
+		expr = globToRegexp(expr)
+	}
+
+	re, err := regexp.Compile(expr)
+	if err != nil {
+		return nil, err
+	}
+
+	return &compiledPattern{re: re, url: []byte(p.URL), host: p.Host}, nil
+}
+
+// PatternHandler compiles patterns at construction time, returning an
+// error for any invalid regular expression, and returns an
+// http.HandlerFunc that matches request paths (and Host, when a
+// pattern specifies one) against them in declared order. The first
+// match has its captured groups substituted into its URL template and
+// the request is redirected there with a 302. If no pattern matches,
+// the fallback http.Handler is called instead.
+func PatternHandler(patterns []Pattern, fallback http.Handler) (http.HandlerFunc, error) {
+	compiled := make([]*compiledPattern, 0, len(patterns))
+
+	for _, p := range patterns {
+		cp, err := compilePattern(p)
+		if err != nil {
+			return nil, err
+		}
+		compiled = append(compiled, cp)
+	}
+
+	return func(w http.ResponseWriter, r *http.Request) {
+		path := []byte(r.URL.Path)
+
+		for _, cp := range compiled {
+			if cp.host != "" && cp.host != r.Host {
+				continue
+			}
+
+			match := cp.re.FindSubmatchIndex(path)
+			if match == nil {
+				continue
+			}
+
+			url := string(cp.re.Expand(nil, cp.url, path, match))
+			http.Redirect(w, r, url, http.StatusFound)
+			return
+		}
+
+		fallback.ServeHTTP(w, r)
+	}, nil
+}
+
+// YamlPatternHandler parses the provided YAML, which may mix exact
+// `path:`/`url:` entries with pattern-matched `pattern:`/`url:`
+// entries, and returns an http.HandlerFunc that checks the exact
+// matches first and falls back to the compiled patterns, in the order
+// they appear in the YAML. The fallback http.Handler is used when
+// neither matches.
+func YamlPatternHandler(yml []byte, fallback http.Handler) (http.HandlerFunc, error) {
+	var redirects []Redirect
+
+	err := ParseYaml(yml, &redirects)
+	if err != nil {
+		return nil, err
+	}
+
+	return redirectsToPatternHandler(redirects, fallback)
+}
+
+// JsonPatternHandler is the JSON equivalent of YamlPatternHandler.
+func JsonPatternHandler(jsn []byte, fallback http.Handler) (http.HandlerFunc, error) {
+	var redirects []Redirect
+
+	err := ParseJson(jsn, &redirects)
+	if err != nil {
+		return nil, err
+	}
+
+	return redirectsToPatternHandler(redirects, fallback)
+}
+
+// redirectsToPatternHandler splits redirects into exact and
+// pattern-matched rules and wires them together as MapHandler ->
+// PatternHandler -> fallback.
+func redirectsToPatternHandler(redirects []Redirect, fallback http.Handler) (http.HandlerFunc, error) {
+	exact := make([]Redirect, 0, len(redirects))
+	var patterns []Pattern
+
+	for _, redirect := range redirects {
+		if redirect.Pattern != "" {
+			patterns = append(patterns, Pattern{
+				Pattern: redirect.Pattern,
+				URL:     redirect.URL,
+				Host:    redirect.Host,
+			})
+			continue
+		}
+
+		exact = append(exact, redirect)
+	}
+
+	patternHandler, err := PatternHandler(patterns, fallback)
+	if err != nil {
+		return nil, err
+	}
+
+	pathsToUrls := RedirectsToMap(exact)
+
+	return MapHandler(pathsToUrls, patternHandler), nil
+}
@@ -0,0 +1,168 @@
+package urlshort
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestPatternHandlerRegexRedirect(t *testing.T) {
+	patterns := []Pattern{
+		{Pattern: "^/gh/([^/]+)/([^/]+)$", URL: "https://github.com/$1/$2"},
+	}
+
+	mux := http.NewServeMux()
+	handler, err := PatternHandler(patterns, mux)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	rr := GetTestResponse(t, handler, "/gh/foo/bar")
+
+	if status := rr.Code; status != http.StatusFound {
+		t.Errorf("Expected status code %v, got %v", http.StatusFound, status)
+	}
+
+	want := "https://github.com/foo/bar"
+	if location := rr.HeaderMap["Location"]; len(location) == 0 || location[0] != want {
+		t.Errorf("Expected url %s got %v", want, location)
+	}
+}
+
+func TestPatternHandlerGlobRedirect(t *testing.T) {
+	patterns := []Pattern{
+		{Pattern: "/user/:id/repo/*rest", URL: "https://example.com/u/$id/r/$rest"},
+	}
+
+	mux := http.NewServeMux()
+	handler, err := PatternHandler(patterns, mux)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	rr := GetTestResponse(t, handler, "/user/42/repo/a/b/c")
+
+	if status := rr.Code; status != http.StatusFound {
+		t.Errorf("Expected status code %v, got %v", http.StatusFound, status)
+	}
+
+	want := "https://example.com/u/42/r/a/b/c"
+	if location := rr.HeaderMap["Location"]; len(location) == 0 || location[0] != want {
+		t.Errorf("Expected url %s got %v", want, location)
+	}
+}
+
+func TestPatternHandlerFallback(t *testing.T) {
+	patterns := []Pattern{
+		{Pattern: "^/gh/([^/]+)$", URL: "https://github.com/$1"},
+	}
+
+	fallbackText := "Hello, world"
+	mux := http.NewServeMux()
+	mux.HandleFunc("/unmatched", func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, fallbackText)
+	})
+
+	handler, err := PatternHandler(patterns, mux)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	rr := GetTestResponse(t, handler, "/unmatched")
+
+	if status := rr.Code; status != http.StatusOK {
+		t.Errorf("Expected status code %v, got %v", http.StatusOK, status)
+	}
+
+	if body := rr.Body; body.String() != fallbackText {
+		t.Errorf("Expected body %s got %s", fallbackText, body.String())
+	}
+}
+
+func TestPatternHandlerDeclaredOrder(t *testing.T) {
+	patterns := []Pattern{
+		{Pattern: "^/multi$", URL: "https://first.example.com"},
+		{Pattern: "^/multi$", URL: "https://second.example.com"},
+	}
+
+	mux := http.NewServeMux()
+	handler, err := PatternHandler(patterns, mux)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	rr := GetTestResponse(t, handler, "/multi")
+
+	want := "https://first.example.com"
+	if location := rr.HeaderMap["Location"]; len(location) == 0 || location[0] != want {
+		t.Errorf("Expected first declared pattern to win, got %v", location)
+	}
+}
+
+func TestPatternHandlerHostFiltering(t *testing.T) {
+	patterns := []Pattern{
+		{Pattern: "^/scoped$", URL: "https://a.example.com", Host: "a.example.com"},
+		{Pattern: "^/scoped$", URL: "https://b.example.com", Host: "b.example.com"},
+	}
+
+	mux := http.NewServeMux()
+	handler, err := PatternHandler(patterns, mux)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	req, err := http.NewRequest("GET", "/scoped", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	req.Host = "b.example.com"
+
+	rr := httptest.NewRecorder()
+	handler.ServeHTTP(rr, req)
+
+	want := "https://b.example.com"
+	if location := rr.HeaderMap["Location"]; len(location) == 0 || location[0] != want {
+		t.Errorf("Expected url %s got %v", want, location)
+	}
+}
+
+func TestPatternHandlerBadRegex(t *testing.T) {
+	patterns := []Pattern{
+		{Pattern: "^/gh/([^/]+$", URL: "https://github.com/$1"},
+	}
+
+	mux := http.NewServeMux()
+	if _, err := PatternHandler(patterns, mux); err == nil {
+		t.Errorf("Expected error from invalid regex pattern")
+	}
+}
+
+func TestRedirectsToPatternHandlerExactBeforePattern(t *testing.T) {
+	redirects := []Redirect{
+		{Path: "/gh/exact", URL: "https://exact.example.com"},
+		{Pattern: "^/gh/([^/]+)$", URL: "https://pattern.example.com/$1"},
+	}
+
+	mux := http.NewServeMux()
+	handler, err := redirectsToPatternHandler(redirects, mux)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// The exact match should win even though the pattern would also match.
+	rr := GetTestResponse(t, handler, "/gh/exact")
+
+	want := "https://exact.example.com"
+	if location := rr.HeaderMap["Location"]; len(location) == 0 || location[0] != want {
+		t.Errorf("Expected exact match to win, got %v", location)
+	}
+
+	// Paths that only the pattern covers should still redirect.
+	rr = GetTestResponse(t, handler, "/gh/other")
+
+	want = "https://pattern.example.com/other"
+	if location := rr.HeaderMap["Location"]; len(location) == 0 || location[0] != want {
+		t.Errorf("Expected url %s got %v", want, location)
+	}
+}
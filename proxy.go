@@ -0,0 +1,175 @@
+package urlshort
+
+import (
+	"context"
+	"net/http"
+	"net/http/httputil"
+	"net/url"
+	"time"
+)
+
+// hopHeaders are the headers defined as hop-by-hop by RFC 7230 6.1;
+// they must not be forwarded by a proxy.
+var hopHeaders = []string{
+	"Connection",
+	"Keep-Alive",
+	"Proxy-Authenticate",
+	"Proxy-Authorization",
+	"Te",
+	"Trailer",
+	"Transfer-Encoding",
+	"Upgrade",
+}
+
+func stripHopHeaders(h http.Header) {
+	for _, header := range hopHeaders {
+		h.Del(header)
+	}
+}
+
+// ProxyOpts configures the reverse proxies built by ProxyHandler.
+type ProxyOpts struct {
+	// Transport is used for outbound requests to proxied targets.
+	// Defaults to http.DefaultTransport when nil.
+	Transport http.RoundTripper
+
+	// Timeout, when positive, bounds how long a proxied request may
+	// take before its context is canceled.
+	Timeout time.Duration
+
+	// ModifyResponse, when set, is called on every response from a
+	// proxied target before it is written back to the client.
+	ModifyResponse func(*http.Response) error
+}
+
+// proxyRule is the compiled form of a single Redirect: either a plain
+// redirect, or a pre-built reverse proxy to URL.
+type proxyRule struct {
+	redirect Redirect
+	proxy    *httputil.ReverseProxy
+}
+
+// ProxyHandler returns an http.HandlerFunc that serves pathsToUrls
+// according to each Redirect's Mode. ModeRedirect entries (the
+// default) issue an http.Redirect using Status, defaulting to 302.
+// ModeProxy entries transparently forward the request to URL using
+// httputil.NewSingleHostReverseProxy, rewriting Host, stripping
+// hop-by-hop headers, and setting X-Forwarded-For/X-Forwarded-Proto.
+// Any path not present in pathsToUrls is passed to the fallback
+// http.Handler. An error is returned if any ModeProxy entry's URL
+// fails to parse.
+func ProxyHandler(pathsToUrls map[string]Redirect, fallback http.Handler, opts ProxyOpts) (http.HandlerFunc, error) {
+	rules := make(map[string]*proxyRule, len(pathsToUrls))
+
+	for path, redirect := range pathsToUrls {
+		rule := &proxyRule{redirect: redirect}
+
+		if redirect.Mode == ModeProxy {
+			target, err := url.Parse(redirect.URL)
+			if err != nil {
+				return nil, err
+			}
+			rule.proxy = newReverseProxy(target, opts)
+		}
+
+		rules[path] = rule
+	}
+
+	return func(w http.ResponseWriter, r *http.Request) {
+		rule, ok := rules[r.URL.Path]
+		if !ok {
+			fallback.ServeHTTP(w, r)
+			return
+		}
+
+		if rule.proxy == nil {
+			status := rule.redirect.Status
+			if status == 0 {
+				status = http.StatusFound
+			}
+			http.Redirect(w, r, rule.redirect.URL, status)
+			return
+		}
+
+		if opts.Timeout > 0 {
+			ctx, cancel := context.WithTimeout(r.Context(), opts.Timeout)
+			defer cancel()
+			r = r.WithContext(ctx)
+		}
+
+		rule.proxy.ServeHTTP(w, r)
+	}, nil
+}
+
+// newReverseProxy builds a reverse proxy to target, layering
+// X-Forwarded-For/X-Forwarded-Proto and hop-by-hop header stripping
+// on top of httputil.NewSingleHostReverseProxy's default director.
+func newReverseProxy(target *url.URL, opts ProxyOpts) *httputil.ReverseProxy {
+	proxy := httputil.NewSingleHostReverseProxy(target)
+
+	baseDirector := proxy.Director
+	proxy.Director = func(req *http.Request) {
+		baseDirector(req)
+		req.Host = target.Host
+		stripHopHeaders(req.Header)
+
+		// ServeHTTP itself appends RemoteAddr's IP to any inbound
+		// X-Forwarded-For once the director returns, so leave that to
+		// it rather than appending here too.
+		proto := "http"
+		if req.TLS != nil {
+			proto = "https"
+		}
+		req.Header.Set("X-Forwarded-Proto", proto)
+	}
+
+	proxy.ModifyResponse = func(resp *http.Response) error {
+		stripHopHeaders(resp.Header)
+
+		if opts.ModifyResponse != nil {
+			return opts.ModifyResponse(resp)
+		}
+
+		return nil
+	}
+
+	if opts.Transport != nil {
+		proxy.Transport = opts.Transport
+	}
+
+	return proxy
+}
+
+// YamlProxyHandler parses the provided YAML into Redirects and
+// returns a ProxyHandler built from them, keyed by Path.
+func YamlProxyHandler(yml []byte, fallback http.Handler, opts ProxyOpts) (http.HandlerFunc, error) {
+	var redirects []Redirect
+
+	err := ParseYaml(yml, &redirects)
+	if err != nil {
+		return nil, err
+	}
+
+	return ProxyHandler(redirectsToProxyMap(redirects), fallback, opts)
+}
+
+// JsonProxyHandler is the JSON equivalent of YamlProxyHandler.
+func JsonProxyHandler(jsn []byte, fallback http.Handler, opts ProxyOpts) (http.HandlerFunc, error) {
+	var redirects []Redirect
+
+	err := ParseJson(jsn, &redirects)
+	if err != nil {
+		return nil, err
+	}
+
+	return ProxyHandler(redirectsToProxyMap(redirects), fallback, opts)
+}
+
+func redirectsToProxyMap(redirects []Redirect) map[string]Redirect {
+	pathsToRedirects := make(map[string]Redirect, len(redirects))
+	for _, redirect := range redirects {
+		pathsToRedirects[redirect.Path] = redirect
+	}
+
+	return pathsToRedirects
+}
@@ -0,0 +1,123 @@
+package urlshort
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestProxyHandlerRedirectMode(t *testing.T) {
+	pathsToUrls := map[string]Redirect{
+		"/default": {Path: "/default", URL: "https://example.com/default"},
+		"/permanent": {
+			Path:   "/permanent",
+			URL:    "https://example.com/permanent",
+			Mode:   ModeRedirect,
+			Status: http.StatusMovedPermanently,
+		},
+	}
+
+	mux := http.NewServeMux()
+	handler, err := ProxyHandler(pathsToUrls, mux, ProxyOpts{})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	rr := GetTestResponse(t, handler, "/default")
+	if status := rr.Code; status != http.StatusFound {
+		t.Errorf("Expected status code %v, got %v", http.StatusFound, status)
+	}
+
+	rr = GetTestResponse(t, handler, "/permanent")
+	if status := rr.Code; status != http.StatusMovedPermanently {
+		t.Errorf("Expected status code %v, got %v", http.StatusMovedPermanently, status)
+	}
+	want := "https://example.com/permanent"
+	if location := rr.HeaderMap["Location"]; len(location) == 0 || location[0] != want {
+		t.Errorf("Expected url %s got %v", want, location)
+	}
+}
+
+func TestProxyHandlerFallback(t *testing.T) {
+	fallbackText := "Hello, world"
+	mux := http.NewServeMux()
+	mux.HandleFunc("/unmatched", func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, fallbackText)
+	})
+
+	handler, err := ProxyHandler(map[string]Redirect{}, mux, ProxyOpts{})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	rr := GetTestResponse(t, handler, "/unmatched")
+
+	if status := rr.Code; status != http.StatusOK {
+		t.Errorf("Expected status code %v, got %v", http.StatusOK, status)
+	}
+
+	if body := rr.Body; body.String() != fallbackText {
+		t.Errorf("Expected body %s got %s", fallbackText, body.String())
+	}
+}
+
+func TestProxyHandlerProxyMode(t *testing.T) {
+	var gotForwardedFor, gotConnection string
+
+	backend := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		gotForwardedFor = r.Header.Get("X-Forwarded-For")
+		gotConnection = r.Header.Get("Connection")
+		fmt.Fprint(w, "proxied response")
+	}))
+	defer backend.Close()
+
+	pathsToUrls := map[string]Redirect{
+		"/proxied": {Path: "/proxied", URL: backend.URL, Mode: ModeProxy},
+	}
+
+	mux := http.NewServeMux()
+	handler, err := ProxyHandler(pathsToUrls, mux, ProxyOpts{})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	req, err := http.NewRequest("GET", "/proxied", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	req.RemoteAddr = "203.0.113.7:1234"
+	req.Header.Set("Connection", "keep-alive")
+	req.Header.Set("X-Forwarded-For", "198.51.100.1")
+
+	rr := httptest.NewRecorder()
+	handler.ServeHTTP(rr, req)
+
+	if status := rr.Code; status != http.StatusOK {
+		t.Errorf("Expected status code %v, got %v", http.StatusOK, status)
+	}
+
+	if body := rr.Body.String(); body != "proxied response" {
+		t.Errorf("Expected body %q got %q", "proxied response", body)
+	}
+
+	wantForwardedFor := "198.51.100.1, 203.0.113.7"
+	if gotForwardedFor != wantForwardedFor {
+		t.Errorf("Expected X-Forwarded-For %q got %q", wantForwardedFor, gotForwardedFor)
+	}
+
+	if gotConnection != "" {
+		t.Errorf("Expected hop-by-hop Connection header to be stripped, got %q", gotConnection)
+	}
+}
+
+func TestProxyHandlerBadProxyURL(t *testing.T) {
+	pathsToUrls := map[string]Redirect{
+		"/broken": {Path: "/broken", URL: "://not-a-url", Mode: ModeProxy},
+	}
+
+	mux := http.NewServeMux()
+	if _, err := ProxyHandler(pathsToUrls, mux, ProxyOpts{}); err == nil {
+		t.Errorf("Expected error from invalid proxy target URL")
+	}
+}
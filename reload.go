@@ -0,0 +1,199 @@
+package urlshort
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"gopkg.in/mgo.v2/bson"
+)
+
+// ErrAlreadyReloading is returned by Reload when a previous reload
+// for the same ReloadableHandler is still in progress.
+var ErrAlreadyReloading = errors.New("urlshort: reload already in progress")
+
+// Source produces the current set of redirects for a ReloadableHandler.
+// It is called once at construction and again on every Reload.
+type Source func() ([]Redirect, error)
+
+// ReloadableHandler wraps a Source and serves requests out of a
+// compiled path->url map that can be swapped out at runtime without
+// interrupting in-flight requests.
+//
+// ServeHTTP only ever takes a read lock; Reload builds the new map
+// off-lock and swaps it in under a single write lock.
+type ReloadableHandler struct {
+	source   Source
+	fallback http.Handler
+
+	mu          sync.RWMutex
+	pathsToUrls map[string]string
+
+	reloading int32
+}
+
+// NewReloadableHandler builds a ReloadableHandler from the given
+// Source, performing an initial load before returning.
+func NewReloadableHandler(source Source, fallback http.Handler) (*ReloadableHandler, error) {
+	h := &ReloadableHandler{
+		source:   source,
+		fallback: fallback,
+	}
+
+	if err := h.Reload(context.Background()); err != nil {
+		return nil, err
+	}
+
+	return h, nil
+}
+
+// ServeHTTP implements http.Handler, looking the request path up in
+// the currently compiled map.
+func (h *ReloadableHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	h.mu.RLock()
+	url, ok := h.pathsToUrls[r.URL.Path]
+	h.mu.RUnlock()
+
+	if ok {
+		http.Redirect(w, r, url, http.StatusFound)
+		return
+	}
+
+	h.fallback.ServeHTTP(w, r)
+}
+
+// Reload fetches the current redirects from the Source, compiles a
+// new path->url map off-lock, then swaps it in under a write lock.
+// Overlapping calls to Reload return ErrAlreadyReloading instead of
+// queuing up behind one another.
+func (h *ReloadableHandler) Reload(ctx context.Context) error {
+	if !atomic.CompareAndSwapInt32(&h.reloading, 0, 1) {
+		return ErrAlreadyReloading
+	}
+	defer atomic.StoreInt32(&h.reloading, 0)
+
+	redirects, err := h.source()
+	if err != nil {
+		return err
+	}
+
+	pathsToUrls := RedirectsToMap(redirects)
+
+	h.mu.Lock()
+	h.pathsToUrls = pathsToUrls
+	h.mu.Unlock()
+
+	return nil
+}
+
+// applyEvent updates the compiled map in place for a single
+// StoreEvent, copying it first so that a reader holding the previous
+// map under RLock is never mutated out from under it.
+func (h *ReloadableHandler) applyEvent(event StoreEvent) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	next := make(map[string]string, len(h.pathsToUrls)+1)
+	for path, url := range h.pathsToUrls {
+		next[path] = url
+	}
+
+	switch event.Type {
+	case StoreEventUpsert:
+		next[event.Redirect.Path] = event.Redirect.URL
+	case StoreEventDelete:
+		delete(next, event.Path)
+	}
+
+	h.pathsToUrls = next
+}
+
+// WatchStore subscribes to store and applies each StoreEvent to the
+// compiled map incrementally, rather than re-running the full Source
+// on every change. It blocks until ctx is done or the event channel
+// closes, so callers should run it in its own goroutine.
+func (h *ReloadableHandler) WatchStore(ctx context.Context, store Store) error {
+	events, err := store.Watch(ctx)
+	if err != nil {
+		return err
+	}
+
+	for {
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case event, ok := <-events:
+			if !ok {
+				return nil
+			}
+			h.applyEvent(event)
+		}
+	}
+}
+
+// WatchFile reloads the handler every interval by re-running its
+// Source, which for a handler built over FileSource(path) re-reads
+// that file. It blocks until ctx is done, so callers should run it in
+// its own goroutine.
+func (h *ReloadableHandler) WatchFile(ctx context.Context, interval time.Duration) error {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-ticker.C:
+			if err := h.Reload(ctx); err != nil && err != ErrAlreadyReloading {
+				return err
+			}
+		}
+	}
+}
+
+// WatchDB reloads the handler every interval by re-running its
+// Source, which for a handler built over DbSource(ctx, r) re-queries r
+// for all redirects. The r argument itself is not read. It blocks
+// until ctx is done, so callers should run it in its own goroutine.
+func (h *ReloadableHandler) WatchDB(ctx context.Context, r *RedirectoryDatabase, interval time.Duration) error {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-ticker.C:
+			if err := h.Reload(ctx); err != nil && err != ErrAlreadyReloading {
+				return err
+			}
+		}
+	}
+}
+
+// FileSource returns a Source that re-parses the YAML file at path on
+// every call.
+func FileSource(path string) Source {
+	return func() ([]Redirect, error) {
+		var redirects []Redirect
+		if err := ParseYamlFile(path, &redirects); err != nil {
+			return nil, err
+		}
+		return redirects, nil
+	}
+}
+
+// DbSource returns a Source that re-queries r for all redirects on
+// every call.
+func DbSource(ctx context.Context, r *RedirectoryDatabase) Source {
+	return func() ([]Redirect, error) {
+		var redirects []Redirect
+		if err := r.Find(ctx, &redirects, bson.M{}); err != nil {
+			return nil, err
+		}
+		return redirects, nil
+	}
+}
@@ -0,0 +1,18 @@
+package urlshort
+
+import (
+	"io"
+
+	"gopkg.in/natefinch/lumberjack.v2"
+)
+
+// NewRotatingWriter returns an io.Writer for LogOpts.Writer that
+// writes to path, rotating the file once it reaches maxSize megabytes
+// and pruning rotated files older than maxAge days.
+func NewRotatingWriter(path string, maxSize, maxAge int) io.Writer {
+	return &lumberjack.Logger{
+		Filename: path,
+		MaxSize:  maxSize,
+		MaxAge:   maxAge,
+	}
+}
@@ -0,0 +1,69 @@
+package urlshort
+
+import (
+	"context"
+	"errors"
+	"net/http"
+)
+
+// StoreEventType identifies the kind of change a StoreEvent describes.
+type StoreEventType string
+
+const (
+	StoreEventUpsert StoreEventType = "upsert"
+	StoreEventDelete StoreEventType = "delete"
+)
+
+// StoreEvent describes a single redirect change observed by Watch.
+// Redirect is populated for StoreEventUpsert; Path is populated for
+// StoreEventDelete.
+type StoreEvent struct {
+	Type     StoreEventType
+	Redirect Redirect
+	Path     string
+}
+
+// ErrWatchUnsupported is returned by Watch implementations that have
+// no change-notification mechanism available.
+var ErrWatchUnsupported = errors.New("urlshort: store does not support Watch")
+
+// Store is a backend capable of serving and maintaining the redirect
+// set behind a handler. Implementations: RedirectoryDatabase (Mongo),
+// InMemoryStore, RedisStore, and SQLStore.
+type Store interface {
+	// Lookup returns the URL for path, and false if no redirect
+	// exists for it.
+	Lookup(ctx context.Context, path string) (string, bool, error)
+
+	// List returns every redirect in the store.
+	List(ctx context.Context) ([]Redirect, error)
+
+	// Upsert creates or replaces the redirect at redirect.Path.
+	Upsert(ctx context.Context, redirect Redirect) error
+
+	// Delete removes the redirect at path, if any.
+	Delete(ctx context.Context, path string) error
+
+	// Watch streams StoreEvents for every subsequent change until
+	// ctx is canceled, at which point the returned channel is
+	// closed. Returns ErrWatchUnsupported if the store has no
+	// change-notification mechanism.
+	Watch(ctx context.Context) (<-chan StoreEvent, error)
+}
+
+// StoreSource adapts a Store into a Source, for use with
+// NewReloadableHandler.
+func StoreSource(ctx context.Context, store Store) Source {
+	return func() ([]Redirect, error) {
+		return store.List(ctx)
+	}
+}
+
+// StoreHandler builds a ReloadableHandler backed by store, performing
+// an initial load before returning. Callers that want the handler to
+// stay in sync with the store as it changes should run
+// handler.WatchStore(ctx, store) in its own goroutine, rather than
+// polling Reload on a timer.
+func StoreHandler(ctx context.Context, store Store, fallback http.Handler) (*ReloadableHandler, error) {
+	return NewReloadableHandler(StoreSource(ctx, store), fallback)
+}
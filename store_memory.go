@@ -0,0 +1,101 @@
+package urlshort
+
+import (
+	"context"
+	"sync"
+)
+
+// InMemoryStore is a Store backed by a plain map, useful for tests and
+// for small deployments that don't need a separate database.
+type InMemoryStore struct {
+	mu        sync.RWMutex
+	redirects map[string]Redirect
+	watchers  []chan StoreEvent
+}
+
+// NewInMemoryStore returns an empty InMemoryStore.
+func NewInMemoryStore() *InMemoryStore {
+	return &InMemoryStore{redirects: map[string]Redirect{}}
+}
+
+func (s *InMemoryStore) Lookup(ctx context.Context, path string) (string, bool, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	redirect, ok := s.redirects[path]
+
+	return redirect.URL, ok, nil
+}
+
+func (s *InMemoryStore) List(ctx context.Context) ([]Redirect, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	redirects := make([]Redirect, 0, len(s.redirects))
+	for _, redirect := range s.redirects {
+		redirects = append(redirects, redirect)
+	}
+
+	return redirects, nil
+}
+
+func (s *InMemoryStore) Upsert(ctx context.Context, redirect Redirect) error {
+	s.mu.Lock()
+	s.redirects[redirect.Path] = redirect
+	s.mu.Unlock()
+
+	s.publish(StoreEvent{Type: StoreEventUpsert, Redirect: redirect})
+
+	return nil
+}
+
+func (s *InMemoryStore) Delete(ctx context.Context, path string) error {
+	s.mu.Lock()
+	delete(s.redirects, path)
+	s.mu.Unlock()
+
+	s.publish(StoreEvent{Type: StoreEventDelete, Path: path})
+
+	return nil
+}
+
+// Watch returns a channel of StoreEvents for every subsequent Upsert
+// or Delete, closing it once ctx is done.
+func (s *InMemoryStore) Watch(ctx context.Context) (<-chan StoreEvent, error) {
+	ch := make(chan StoreEvent, 16)
+
+	s.mu.Lock()
+	s.watchers = append(s.watchers, ch)
+	s.mu.Unlock()
+
+	go func() {
+		<-ctx.Done()
+
+		s.mu.Lock()
+		defer s.mu.Unlock()
+
+		for i, w := range s.watchers {
+			if w == ch {
+				s.watchers = append(s.watchers[:i], s.watchers[i+1:]...)
+				break
+			}
+		}
+		close(ch)
+	}()
+
+	return ch, nil
+}
+
+// publish fans event out to every active watcher, dropping it for any
+// watcher whose buffer is full rather than blocking Upsert/Delete.
+func (s *InMemoryStore) publish(event StoreEvent) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	for _, ch := range s.watchers {
+		select {
+		case ch <- event:
+		default:
+		}
+	}
+}
@@ -0,0 +1,149 @@
+package urlshort
+
+import (
+	"context"
+	"testing"
+	"time"
+)
+
+func TestInMemoryStoreLookupUpsertDelete(t *testing.T) {
+	store := NewInMemoryStore()
+	ctx := context.Background()
+
+	if _, ok, err := store.Lookup(ctx, "/missing"); err != nil || ok {
+		t.Fatalf("Expected no match for /missing, got ok=%v err=%v", ok, err)
+	}
+
+	redirect := Redirect{Path: "/urlshort", URL: "https://example.com"}
+	if err := store.Upsert(ctx, redirect); err != nil {
+		t.Fatal(err)
+	}
+
+	url, ok, err := store.Lookup(ctx, "/urlshort")
+	if err != nil || !ok || url != redirect.URL {
+		t.Fatalf("Expected (%s, true, nil), got (%s, %v, %v)", redirect.URL, url, ok, err)
+	}
+
+	redirects, err := store.List(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(redirects) != 1 || redirects[0] != redirect {
+		t.Fatalf("Expected [%v], got %v", redirect, redirects)
+	}
+
+	if err := store.Delete(ctx, "/urlshort"); err != nil {
+		t.Fatal(err)
+	}
+
+	if _, ok, err := store.Lookup(ctx, "/urlshort"); err != nil || ok {
+		t.Fatalf("Expected no match after delete, got ok=%v err=%v", ok, err)
+	}
+}
+
+func TestInMemoryStoreWatchFanOut(t *testing.T) {
+	store := NewInMemoryStore()
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	events1, err := store.Watch(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	events2, err := store.Watch(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	redirect := Redirect{Path: "/urlshort", URL: "https://example.com"}
+	if err := store.Upsert(context.Background(), redirect); err != nil {
+		t.Fatal(err)
+	}
+
+	for i, events := range []<-chan StoreEvent{events1, events2} {
+		select {
+		case event := <-events:
+			if event.Type != StoreEventUpsert || event.Redirect != redirect {
+				t.Errorf("watcher %d: unexpected event %+v", i, event)
+			}
+		case <-time.After(time.Second):
+			t.Errorf("watcher %d: timed out waiting for event", i)
+		}
+	}
+
+	if err := store.Delete(context.Background(), "/urlshort"); err != nil {
+		t.Fatal(err)
+	}
+
+	select {
+	case event := <-events1:
+		if event.Type != StoreEventDelete || event.Path != "/urlshort" {
+			t.Errorf("unexpected delete event %+v", event)
+		}
+	case <-time.After(time.Second):
+		t.Errorf("timed out waiting for delete event")
+	}
+}
+
+func TestInMemoryStoreWatchDropsOnFullBuffer(t *testing.T) {
+	store := NewInMemoryStore()
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	events, err := store.Watch(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// Flood well past the watcher's buffer without ever draining it;
+	// publish must drop rather than block.
+	done := make(chan struct{})
+	go func() {
+		for i := 0; i < 100; i++ {
+			store.Upsert(context.Background(), Redirect{Path: "/x", URL: "https://example.com"})
+		}
+		close(done)
+	}()
+
+	select {
+	case <-done:
+	case <-time.After(time.Second):
+		t.Fatal("Upsert blocked instead of dropping events for a full watcher")
+	}
+
+	// Drain whatever made it through; the buffer caps what's queued.
+	drained := 0
+	for {
+		select {
+		case <-events:
+			drained++
+		default:
+			if drained == 0 {
+				t.Error("Expected at least one event to have been delivered")
+			}
+			return
+		}
+	}
+}
+
+func TestInMemoryStoreWatchClosesOnContextDone(t *testing.T) {
+	store := NewInMemoryStore()
+	ctx, cancel := context.WithCancel(context.Background())
+
+	events, err := store.Watch(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	cancel()
+
+	select {
+	case _, ok := <-events:
+		if ok {
+			t.Errorf("Expected channel to be closed with no pending events")
+		}
+	case <-time.After(time.Second):
+		t.Fatal("Expected channel to close after context cancellation")
+	}
+}
@@ -0,0 +1,126 @@
+package urlshort
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/go-redis/redis/v8"
+)
+
+// redisRedirectsKey is the hash that backs RedisStore: field names are
+// paths, field values are target URLs.
+const redisRedirectsKey = "urlshort:redirects"
+
+// RedisStore is a Store backed by a Redis hash, suitable for sharing
+// redirects across multiple urlshort instances.
+type RedisStore struct {
+	client *redis.Client
+	key    string
+}
+
+// NewRedisStore returns a RedisStore backed by client, using the
+// `urlshort:redirects` hash.
+func NewRedisStore(client *redis.Client) *RedisStore {
+	return &RedisStore{client: client, key: redisRedirectsKey}
+}
+
+func (s *RedisStore) Lookup(ctx context.Context, path string) (string, bool, error) {
+	url, err := s.client.HGet(ctx, s.key, path).Result()
+	if err == redis.Nil {
+		return "", false, nil
+	}
+	if err != nil {
+		return "", false, err
+	}
+
+	return url, true, nil
+}
+
+func (s *RedisStore) List(ctx context.Context) ([]Redirect, error) {
+	fields, err := s.client.HGetAll(ctx, s.key).Result()
+	if err != nil {
+		return nil, err
+	}
+
+	redirects := make([]Redirect, 0, len(fields))
+	for path, url := range fields {
+		redirects = append(redirects, Redirect{Path: path, URL: url})
+	}
+
+	return redirects, nil
+}
+
+func (s *RedisStore) Upsert(ctx context.Context, redirect Redirect) error {
+	return s.client.HSet(ctx, s.key, redirect.Path, redirect.URL).Err()
+}
+
+func (s *RedisStore) Delete(ctx context.Context, path string) error {
+	return s.client.HDel(ctx, s.key, path).Err()
+}
+
+// Watch subscribes to keyspace notifications for s.key, which
+// requires `notify-keyspace-events Kh` (or better) to be enabled on
+// the Redis server. Keyspace notifications only say the hash changed,
+// not which field, so on every notification Watch re-lists the hash
+// and diffs it against the previous snapshot to compute upserts and
+// deletes.
+func (s *RedisStore) Watch(ctx context.Context) (<-chan StoreEvent, error) {
+	pubsub := s.client.PSubscribe(ctx, fmt.Sprintf("__keyspace@*__:%s", s.key))
+
+	events := make(chan StoreEvent)
+
+	go func() {
+		defer close(events)
+		defer pubsub.Close()
+
+		prev := map[string]string{}
+		if redirects, err := s.List(ctx); err == nil {
+			for _, redirect := range redirects {
+				prev[redirect.Path] = redirect.URL
+			}
+		}
+		ch := pubsub.Channel()
+
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case _, ok := <-ch:
+				if !ok {
+					return
+				}
+
+				redirects, err := s.List(ctx)
+				if err != nil {
+					continue
+				}
+
+				next := make(map[string]string, len(redirects))
+				for _, redirect := range redirects {
+					next[redirect.Path] = redirect.URL
+					if prevURL, ok := prev[redirect.Path]; !ok || prevURL != redirect.URL {
+						select {
+						case events <- StoreEvent{Type: StoreEventUpsert, Redirect: redirect}:
+						case <-ctx.Done():
+							return
+						}
+					}
+				}
+
+				for path := range prev {
+					if _, ok := next[path]; !ok {
+						select {
+						case events <- StoreEvent{Type: StoreEventDelete, Path: path}:
+						case <-ctx.Done():
+							return
+						}
+					}
+				}
+
+				prev = next
+			}
+		}
+	}()
+
+	return events, nil
+}
@@ -0,0 +1,82 @@
+package urlshort
+
+import (
+	"context"
+	"database/sql"
+)
+
+// SQLStore is a Store backed by a `redirects(path PK, url, mode,
+// status)` table, reached through database/sql.
+type SQLStore struct {
+	db *sql.DB
+}
+
+// NewSQLStore returns a SQLStore backed by db. The redirects table is
+// expected to already exist.
+func NewSQLStore(db *sql.DB) *SQLStore {
+	return &SQLStore{db: db}
+}
+
+func (s *SQLStore) Lookup(ctx context.Context, path string) (string, bool, error) {
+	var url string
+
+	err := s.db.QueryRowContext(ctx, `SELECT url FROM redirects WHERE path = ?`, path).Scan(&url)
+	if err == sql.ErrNoRows {
+		return "", false, nil
+	}
+	if err != nil {
+		return "", false, err
+	}
+
+	return url, true, nil
+}
+
+func (s *SQLStore) List(ctx context.Context) ([]Redirect, error) {
+	rows, err := s.db.QueryContext(ctx, `SELECT path, url, mode, status FROM redirects`)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var redirects []Redirect
+	for rows.Next() {
+		var (
+			redirect Redirect
+			mode     sql.NullString
+			status   sql.NullInt64
+		)
+
+		if err := rows.Scan(&redirect.Path, &redirect.URL, &mode, &status); err != nil {
+			return nil, err
+		}
+
+		redirect.Mode = mode.String
+		redirect.Status = int(status.Int64)
+		redirects = append(redirects, redirect)
+	}
+
+	return redirects, rows.Err()
+}
+
+func (s *SQLStore) Upsert(ctx context.Context, redirect Redirect) error {
+	_, err := s.db.ExecContext(ctx, `
+		INSERT INTO redirects (path, url, mode, status) VALUES (?, ?, ?, ?)
+		ON CONFLICT(path) DO UPDATE SET url = excluded.url, mode = excluded.mode, status = excluded.status
+	`, redirect.Path, redirect.URL, redirect.Mode, redirect.Status)
+
+	return err
+}
+
+func (s *SQLStore) Delete(ctx context.Context, path string) error {
+	_, err := s.db.ExecContext(ctx, `DELETE FROM redirects WHERE path = ?`, path)
+
+	return err
+}
+
+// Watch always returns ErrWatchUnsupported: database/sql has no
+// portable change-notification mechanism. Callers needing live
+// updates should poll List on an interval via
+// ReloadableHandler.WatchFile/WatchDB-style periodic Reload instead.
+func (s *SQLStore) Watch(ctx context.Context) (<-chan StoreEvent, error) {
+	return nil, ErrWatchUnsupported
+}